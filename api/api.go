@@ -0,0 +1,14 @@
+package api
+
+// API is implemented by every supported panel client
+type API interface {
+	Describe() ClientInfo
+	Debug()
+	GetNodeInfo() (nodeInfo *NodeInfo, err error)
+	GetUserList() (userList *[]UserInfo, err error)
+	ReportNodeStatus(nodeStatus *NodeStatus) (err error)
+	ReportNodeOnlineUsers(onlineUserList *[]OnlineUser) (err error)
+	ReportUserTraffic(userTraffic *[]UserTraffic) (err error)
+	GetNodeRule() (ruleList *[]DetectRule, urlList *[]string, err error)
+	ReportIllegal(detectResultList *[]DetectResult) (err error)
+}
@@ -0,0 +1,35 @@
+package api
+
+// Config is the common configuration shared by every panel API client
+type Config struct {
+	APIHost      string
+	NodeID       int
+	Key          string
+	NodeType     string
+	Timeout      int
+	EnableVless  bool
+	EnableXTLS   bool
+	SpeedLimit   float64
+	DeviceLimit  int
+	RuleListPath string
+	// CacheBackend selects the Store implementation used to survive panel
+	// outages: "", "bolt", "etcd" or "consul". Empty disables caching/spooling.
+	CacheBackend string
+	// CachePath is the BoltDB file path (CacheBackend "bolt") or a
+	// comma-separated list of addresses (CacheBackend "etcd"/"consul").
+	CachePath string
+	// CacheTTL is the maximum age, in seconds since it was stored, at which a
+	// cached GetNodeInfo/GetUserList response is still served after a failed
+	// panel request. <= 0 serves it regardless of age.
+	CacheTTL int
+	// SpoolSize caps how many pending ReportUserTraffic/ReportNodeOnlineUsers/
+	// ReportIllegal batches are kept per call while the panel is unreachable.
+	SpoolSize int
+	// UseWebSocket subscribes to the panel's userList/node stream over a
+	// WebSocket instead of polling GetUserList/GetNodeInfo with plain GETs.
+	UseWebSocket bool
+	// AuditLogPath, if set, appends a JSON-lines record of every illegal
+	// report to this local file, so operators can correlate panel-reported
+	// triggers with local evidence.
+	AuditLogPath string
+}
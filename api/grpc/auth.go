@@ -0,0 +1,79 @@
+package grpc
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// maxClockSkew bounds how far a request's "timestamp" metadata may drift
+// from the server's clock, the same replay-window idea createCommonRequest
+// relies on for HTTP.
+const maxClockSkew = 5 * time.Minute
+
+// authenticate checks the incoming "key"/"timestamp" metadata the same way
+// the panel's HTTP endpoints check their key/timestamp headers.
+func authenticate(ctx context.Context, key string) error {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "missing metadata")
+	}
+
+	if !hasValue(md, "key", key) {
+		return status.Error(codes.Unauthenticated, "invalid key")
+	}
+
+	values := md.Get("timestamp")
+	if len(values) == 0 {
+		return status.Error(codes.Unauthenticated, "missing timestamp")
+	}
+	ts, err := strconv.ParseInt(values[0], 10, 64)
+	if err != nil {
+		return status.Error(codes.Unauthenticated, "invalid timestamp")
+	}
+	skew := time.Since(time.Unix(ts, 0))
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > maxClockSkew {
+		return status.Error(codes.Unauthenticated, "timestamp outside allowed clock skew")
+	}
+
+	return nil
+}
+
+func hasValue(md metadata.MD, name, want string) bool {
+	for _, v := range md.Get(name) {
+		if v == want {
+			return true
+		}
+	}
+	return false
+}
+
+// unaryAuthInterceptor rejects unary RPCs whose key/timestamp metadata
+// doesn't check out before the call reaches backend.
+func unaryAuthInterceptor(key string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := authenticate(ctx, key); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// streamAuthInterceptor is the streaming-RPC counterpart of
+// unaryAuthInterceptor, used for WatchNodeInfo/WatchUserList.
+func streamAuthInterceptor(key string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := authenticate(ss.Context(), key); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
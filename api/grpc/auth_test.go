@@ -0,0 +1,41 @@
+package grpc
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+)
+
+func TestAuthenticate(t *testing.T) {
+	now := time.Now().Unix()
+
+	cases := []struct {
+		name    string
+		md      metadata.MD
+		wantErr bool
+	}{
+		{"valid key and timestamp", metadata.Pairs("key", "secret", "timestamp", strconv.FormatInt(now, 10)), false},
+		{"wrong key", metadata.Pairs("key", "wrong", "timestamp", strconv.FormatInt(now, 10)), true},
+		{"missing key", metadata.Pairs("timestamp", strconv.FormatInt(now, 10)), true},
+		{"missing timestamp", metadata.Pairs("key", "secret"), true},
+		{"non-numeric timestamp", metadata.Pairs("key", "secret", "timestamp", "not-a-number"), true},
+		{"stale timestamp", metadata.Pairs("key", "secret", "timestamp", strconv.FormatInt(now-int64(maxClockSkew.Seconds())-60, 10)), true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), c.md)
+			err := authenticate(ctx, "secret")
+			if (err != nil) != c.wantErr {
+				t.Errorf("authenticate() err = %v, wantErr %v", err, c.wantErr)
+			}
+		})
+	}
+
+	if err := authenticate(context.Background(), "secret"); err == nil {
+		t.Error("authenticate() with no incoming metadata should fail")
+	}
+}
@@ -0,0 +1,158 @@
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/realldz/XrayR/api"
+	"github.com/realldz/XrayR/api/grpc/pb"
+)
+
+// Client talks to a Server over gRPC, implementing api.API so it can be
+// used as a drop-in replacement for an XrayR node that would otherwise
+// poll the panel directly.
+type Client struct {
+	conn *grpc.ClientConn
+	rpc  pb.XrayRControllerClient
+	info api.ClientInfo
+}
+
+// NewClient dials the controller at addr (host:port) using TLS client
+// certificates for transport security. The existing Key/timestamp headers
+// are still sent, carried as gRPC metadata on every call.
+func NewClient(addr string, tlsConfig *tls.Config, info api.ClientInfo) (*Client, error) {
+	conn, err := grpc.Dial(addr,
+		grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)),
+		grpc.WithDefaultCallOptions(grpc.CallContentSubtype(pb.CodecName)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("dial controller %s failed: %s", addr, err)
+	}
+	return &Client{
+		conn: conn,
+		rpc:  pb.NewXrayRControllerClient(conn),
+		info: info,
+	}, nil
+}
+
+// Close tears down the underlying gRPC connection
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Describe implements api.API
+func (c *Client) Describe() api.ClientInfo {
+	return c.info
+}
+
+// Debug implements api.API; it is a no-op, this client has no request/
+// response logging.
+func (c *Client) Debug() {}
+
+func (c *Client) ctx() context.Context {
+	ctx := context.Background()
+	return metadata.AppendToOutgoingContext(ctx,
+		"key", c.info.Key,
+		"timestamp", strconv.FormatInt(time.Now().Unix(), 10),
+	)
+}
+
+func (c *Client) nodeRequest() *pb.NodeRequest {
+	return &pb.NodeRequest{NodeId: int32(c.info.NodeID), NodeType: c.info.NodeType}
+}
+
+// GetNodeInfo implements api.API
+func (c *Client) GetNodeInfo() (*api.NodeInfo, error) {
+	resp, err := c.rpc.GetNodeInfo(c.ctx(), c.nodeRequest())
+	if err != nil {
+		return nil, err
+	}
+	return nodeInfoFromPB(resp), nil
+}
+
+// GetUserList implements api.API
+func (c *Client) GetUserList() (*[]api.UserInfo, error) {
+	resp, err := c.rpc.GetUserList(c.ctx(), c.nodeRequest())
+	if err != nil {
+		return nil, err
+	}
+	userList := make([]api.UserInfo, len(resp.Users))
+	for i, u := range resp.Users {
+		userList[i] = userInfoFromPB(u)
+	}
+	return &userList, nil
+}
+
+// ReportNodeStatus implements api.API
+func (c *Client) ReportNodeStatus(nodeStatus *api.NodeStatus) error {
+	_, err := c.rpc.ReportNodeStatus(c.ctx(), &pb.NodeStatus{
+		Cpu:    nodeStatus.CPU,
+		Mem:    nodeStatus.Mem,
+		Disk:   nodeStatus.Disk,
+		Uptime: nodeStatus.Uptime,
+	})
+	return err
+}
+
+// ReportNodeOnlineUsers implements api.API
+func (c *Client) ReportNodeOnlineUsers(onlineUserList *[]api.OnlineUser) error {
+	users := make([]*pb.OnlineUser, len(*onlineUserList))
+	for i, u := range *onlineUserList {
+		users[i] = &pb.OnlineUser{Uid: int32(u.UID), Ip: u.IP}
+	}
+	_, err := c.rpc.ReportNodeOnlineUsers(c.ctx(), &pb.OnlineUserList{Users: users})
+	return err
+}
+
+// ReportUserTraffic implements api.API
+func (c *Client) ReportUserTraffic(userTraffic *[]api.UserTraffic) error {
+	traffic := make([]*pb.UserTraffic, len(*userTraffic))
+	for i, t := range *userTraffic {
+		traffic[i] = &pb.UserTraffic{Uid: int32(t.UID), Upload: t.Upload, Download: t.Download}
+	}
+	_, err := c.rpc.ReportUserTraffic(c.ctx(), &pb.UserTrafficList{Traffic: traffic})
+	return err
+}
+
+// GetNodeRule implements api.API
+func (c *Client) GetNodeRule() (*[]api.DetectRule, *[]string, error) {
+	resp, err := c.rpc.GetNodeRule(c.ctx(), c.nodeRequest())
+	if err != nil {
+		return nil, nil, err
+	}
+	ruleList := make([]api.DetectRule, 0, len(resp.Rules))
+	for _, r := range resp.Rules {
+		pattern, err := regexp.Compile(r.Pattern)
+		if err != nil {
+			continue
+		}
+		ruleList = append(ruleList, api.DetectRule{ID: int(r.Id), Pattern: pattern})
+	}
+	return &ruleList, nil, nil
+}
+
+// ReportIllegal implements api.API
+func (c *Client) ReportIllegal(detectResultList *[]api.DetectResult) error {
+	results := make([]*pb.DetectResult, len(*detectResultList))
+	for i, r := range *detectResultList {
+		results[i] = &pb.DetectResult{
+			RuleId:    int32(r.RuleID),
+			Uid:       int32(r.UID),
+			Reason:    r.Reason,
+			Host:      r.Host,
+			Path:      r.Path,
+			Protocol:  r.Protocol,
+			Timestamp: r.Timestamp,
+		}
+	}
+	_, err := c.rpc.ReportIllegal(c.ctx(), &pb.DetectResultList{Results: results})
+	return err
+}
@@ -0,0 +1,80 @@
+package grpc
+
+import (
+	"github.com/realldz/XrayR/api"
+	"github.com/realldz/XrayR/api/grpc/pb"
+)
+
+func nodeInfoToPB(n *api.NodeInfo) *pb.NodeInfo {
+	return &pb.NodeInfo{
+		NodeType:          n.NodeType,
+		NodeId:            int32(n.NodeID),
+		Port:              int32(n.Port),
+		SpeedLimit:        n.SpeedLimit,
+		DeviceLimit:       int32(n.DeviceLimit),
+		AlterId:           int32(n.AlterID),
+		TransportProtocol: n.TransportProtocol,
+		FakeType:          n.FakeType,
+		CypherMethod:      n.CypherMethod,
+		ServiceName:       n.ServiceName,
+		Header:            n.Header,
+		EnableTls:         n.EnableTLS,
+		TlsType:           n.TLSType,
+		Path:              n.Path,
+		Host:              n.Host,
+		ServerName:        n.ServerName,
+		EnableVless:       n.EnableVless,
+		Flow:              n.Flow,
+		SsPassword:        n.SSPassword,
+		SsMethod:          n.SSMethod,
+	}
+}
+
+func nodeInfoFromPB(n *pb.NodeInfo) *api.NodeInfo {
+	return &api.NodeInfo{
+		NodeType:          n.NodeType,
+		NodeID:            int(n.NodeId),
+		Port:              int(n.Port),
+		SpeedLimit:        n.SpeedLimit,
+		DeviceLimit:       int(n.DeviceLimit),
+		AlterID:           int(n.AlterId),
+		TransportProtocol: n.TransportProtocol,
+		FakeType:          n.FakeType,
+		CypherMethod:      n.CypherMethod,
+		ServiceName:       n.ServiceName,
+		Header:            n.Header,
+		EnableTLS:         n.EnableTls,
+		TLSType:           n.TlsType,
+		Path:              n.Path,
+		Host:              n.Host,
+		ServerName:        n.ServerName,
+		EnableVless:       n.EnableVless,
+		Flow:              n.Flow,
+		SSPassword:        n.SsPassword,
+		SSMethod:          n.SsMethod,
+	}
+}
+
+func userInfoToPB(u api.UserInfo) *pb.UserInfo {
+	return &pb.UserInfo{
+		Uid:         int32(u.UID),
+		Email:       u.Email,
+		Uuid:        u.UUID,
+		Passwd:      u.Passwd,
+		SpeedLimit:  u.SpeedLimit,
+		DeviceLimit: int32(u.DeviceLimit),
+		Flow:        u.Flow,
+	}
+}
+
+func userInfoFromPB(u *pb.UserInfo) api.UserInfo {
+	return api.UserInfo{
+		UID:         int(u.Uid),
+		Email:       u.Email,
+		UUID:        u.Uuid,
+		Passwd:      u.Passwd,
+		SpeedLimit:  u.SpeedLimit,
+		DeviceLimit: int(u.DeviceLimit),
+		Flow:        u.Flow,
+	}
+}
@@ -0,0 +1,10 @@
+// Package grpc exposes the api.API surface as a gRPC service, so one
+// central XrayR "controller" can talk to the real panel and many edge
+// XrayR nodes can subscribe to it over gRPC instead of each hammering the
+// panel directly.
+//
+// proto/xrayr.proto is the service contract; the pb subpackage hand-implements
+// it (wire types plus client/server bindings over a JSON codec) since no
+// protoc toolchain was available when this was written. See pb's package
+// doc for how to regenerate it for real.
+package grpc
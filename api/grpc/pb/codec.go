@@ -0,0 +1,36 @@
+package pb
+
+import (
+	"encoding/json"
+
+	"google.golang.org/grpc/encoding"
+)
+
+// CodecName is the gRPC content-subtype this package's client calls must
+// opt into via grpc.CallContentSubtype; a server picks the registered codec
+// from each request's content-subtype. It deliberately isn't "proto": that name is grpc-go's process-wide
+// default, and XrayR embeds xray-core, which runs its own real-protobuf
+// gRPC services in the same binary. Overriding the default codec there
+// would silently break them.
+const CodecName = "xrayr-json"
+
+// jsonCodec marshals this package's hand-written structs as JSON instead
+// of real protobuf wire format.
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string { return CodecName }
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, v)
+}
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
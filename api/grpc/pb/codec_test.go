@@ -0,0 +1,24 @@
+package pb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestJSONCodecRoundTrip(t *testing.T) {
+	var codec jsonCodec
+
+	in := &NodeInfo{NodeType: "V2ray", NodeId: 1, Port: 443, Flow: "xtls-rprx-vision"}
+	data, err := codec.Marshal(in)
+	if err != nil {
+		t.Fatalf("Marshal failed: %s", err)
+	}
+
+	var out NodeInfo
+	if err := codec.Unmarshal(data, &out); err != nil {
+		t.Fatalf("Unmarshal failed: %s", err)
+	}
+	if !reflect.DeepEqual(out, *in) {
+		t.Errorf("round trip = %+v, want %+v", out, *in)
+	}
+}
@@ -0,0 +1,107 @@
+// Package pb holds the wire types and service interfaces for the
+// XrayRController gRPC service defined in proto/xrayr.proto.
+//
+// These are hand-written rather than protoc-generated: the sandbox this
+// package was written in has no protoc/protoc-gen-go toolchain available.
+// The field names and JSON tags mirror the .proto exactly, and codec.go
+// swaps gRPC's wire codec for plain JSON so the service still speaks a
+// well-defined, versionable wire format without depending on protobuf
+// codegen. Regenerate this package for real once protoc is available:
+//
+//	protoc --go_out=. --go-grpc_out=. proto/xrayr.proto
+package pb
+
+// NodeRequest identifies the node a controller RPC applies to
+type NodeRequest struct {
+	NodeId   int32  `json:"node_id"`
+	NodeType string `json:"node_type"`
+}
+
+type NodeInfo struct {
+	NodeType          string `json:"node_type"`
+	NodeId            int32  `json:"node_id"`
+	Port              int32  `json:"port"`
+	SpeedLimit        uint64 `json:"speed_limit"`
+	DeviceLimit       int32  `json:"device_limit"`
+	AlterId           int32  `json:"alter_id"`
+	TransportProtocol string `json:"transport_protocol"`
+	FakeType          string `json:"fake_type"`
+	CypherMethod      string `json:"cypher_method"`
+	ServiceName       string `json:"service_name"`
+	Header            []byte `json:"header"`
+	EnableTls         bool   `json:"enable_tls"`
+	TlsType           string `json:"tls_type"`
+	Path              string `json:"path"`
+	Host              string `json:"host"`
+	ServerName        string `json:"server_name"`
+	EnableVless       bool   `json:"enable_vless"`
+	Flow              string `json:"flow"`
+	SsPassword        string `json:"ss_password"`
+	SsMethod          string `json:"ss_method"`
+}
+
+type UserInfo struct {
+	Uid         int32  `json:"uid"`
+	Email       string `json:"email"`
+	Uuid        string `json:"uuid"`
+	Passwd      string `json:"passwd"`
+	SpeedLimit  uint64 `json:"speed_limit"`
+	DeviceLimit int32  `json:"device_limit"`
+	Flow        string `json:"flow"`
+}
+
+type UserList struct {
+	Users []*UserInfo `json:"users"`
+}
+
+type NodeStatus struct {
+	Cpu    float64 `json:"cpu"`
+	Mem    float64 `json:"mem"`
+	Disk   float64 `json:"disk"`
+	Uptime uint64  `json:"uptime"`
+}
+
+type OnlineUser struct {
+	Uid int32  `json:"uid"`
+	Ip  string `json:"ip"`
+}
+
+type OnlineUserList struct {
+	Users []*OnlineUser `json:"users"`
+}
+
+type UserTraffic struct {
+	Uid      int32 `json:"uid"`
+	Upload   int64 `json:"upload"`
+	Download int64 `json:"download"`
+}
+
+type UserTrafficList struct {
+	Traffic []*UserTraffic `json:"traffic"`
+}
+
+type DetectRule struct {
+	Id      int32  `json:"id"`
+	Pattern string `json:"pattern"`
+}
+
+type DetectRuleList struct {
+	Rules []*DetectRule `json:"rules"`
+}
+
+type DetectResult struct {
+	RuleId    int32  `json:"rule_id"`
+	Uid       int32  `json:"uid"`
+	Reason    string `json:"reason"`
+	Host      string `json:"host"`
+	Path      string `json:"path"`
+	Protocol  string `json:"protocol"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+type DetectResultList struct {
+	Results []*DetectResult `json:"results"`
+}
+
+// Empty stands in for google.protobuf.Empty
+type Empty struct{}
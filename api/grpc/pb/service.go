@@ -0,0 +1,378 @@
+package pb
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+const (
+	serviceName             = "xrayr.XrayRController"
+	methodGetNodeInfo       = "/" + serviceName + "/GetNodeInfo"
+	methodGetUserList       = "/" + serviceName + "/GetUserList"
+	methodReportNodeStatus  = "/" + serviceName + "/ReportNodeStatus"
+	methodReportNodeOnline  = "/" + serviceName + "/ReportNodeOnlineUsers"
+	methodReportUserTraffic = "/" + serviceName + "/ReportUserTraffic"
+	methodGetNodeRule       = "/" + serviceName + "/GetNodeRule"
+	methodReportIllegal     = "/" + serviceName + "/ReportIllegal"
+	methodWatchNodeInfo     = "/" + serviceName + "/WatchNodeInfo"
+	methodWatchUserList     = "/" + serviceName + "/WatchUserList"
+)
+
+// XrayRControllerClient is the client API for the XrayRController service
+type XrayRControllerClient interface {
+	GetNodeInfo(ctx context.Context, in *NodeRequest, opts ...grpc.CallOption) (*NodeInfo, error)
+	GetUserList(ctx context.Context, in *NodeRequest, opts ...grpc.CallOption) (*UserList, error)
+	ReportNodeStatus(ctx context.Context, in *NodeStatus, opts ...grpc.CallOption) (*Empty, error)
+	ReportNodeOnlineUsers(ctx context.Context, in *OnlineUserList, opts ...grpc.CallOption) (*Empty, error)
+	ReportUserTraffic(ctx context.Context, in *UserTrafficList, opts ...grpc.CallOption) (*Empty, error)
+	GetNodeRule(ctx context.Context, in *NodeRequest, opts ...grpc.CallOption) (*DetectRuleList, error)
+	ReportIllegal(ctx context.Context, in *DetectResultList, opts ...grpc.CallOption) (*Empty, error)
+	WatchNodeInfo(ctx context.Context, in *NodeRequest, opts ...grpc.CallOption) (XrayRController_WatchNodeInfoClient, error)
+	WatchUserList(ctx context.Context, in *NodeRequest, opts ...grpc.CallOption) (XrayRController_WatchUserListClient, error)
+}
+
+type xrayRControllerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewXrayRControllerClient builds a client bound to cc
+func NewXrayRControllerClient(cc grpc.ClientConnInterface) XrayRControllerClient {
+	return &xrayRControllerClient{cc: cc}
+}
+
+func (c *xrayRControllerClient) GetNodeInfo(ctx context.Context, in *NodeRequest, opts ...grpc.CallOption) (*NodeInfo, error) {
+	out := new(NodeInfo)
+	if err := c.cc.Invoke(ctx, methodGetNodeInfo, in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *xrayRControllerClient) GetUserList(ctx context.Context, in *NodeRequest, opts ...grpc.CallOption) (*UserList, error) {
+	out := new(UserList)
+	if err := c.cc.Invoke(ctx, methodGetUserList, in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *xrayRControllerClient) ReportNodeStatus(ctx context.Context, in *NodeStatus, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	if err := c.cc.Invoke(ctx, methodReportNodeStatus, in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *xrayRControllerClient) ReportNodeOnlineUsers(ctx context.Context, in *OnlineUserList, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	if err := c.cc.Invoke(ctx, methodReportNodeOnline, in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *xrayRControllerClient) ReportUserTraffic(ctx context.Context, in *UserTrafficList, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	if err := c.cc.Invoke(ctx, methodReportUserTraffic, in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *xrayRControllerClient) GetNodeRule(ctx context.Context, in *NodeRequest, opts ...grpc.CallOption) (*DetectRuleList, error) {
+	out := new(DetectRuleList)
+	if err := c.cc.Invoke(ctx, methodGetNodeRule, in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *xrayRControllerClient) ReportIllegal(ctx context.Context, in *DetectResultList, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	if err := c.cc.Invoke(ctx, methodReportIllegal, in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *xrayRControllerClient) WatchNodeInfo(ctx context.Context, in *NodeRequest, opts ...grpc.CallOption) (XrayRController_WatchNodeInfoClient, error) {
+	stream, err := c.cc.NewStream(ctx, &xrayRControllerServiceDesc.Streams[0], methodWatchNodeInfo, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &xrayRControllerWatchNodeInfoClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type XrayRController_WatchNodeInfoClient interface {
+	Recv() (*NodeInfo, error)
+	grpc.ClientStream
+}
+
+type xrayRControllerWatchNodeInfoClient struct {
+	grpc.ClientStream
+}
+
+func (x *xrayRControllerWatchNodeInfoClient) Recv() (*NodeInfo, error) {
+	m := new(NodeInfo)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *xrayRControllerClient) WatchUserList(ctx context.Context, in *NodeRequest, opts ...grpc.CallOption) (XrayRController_WatchUserListClient, error) {
+	stream, err := c.cc.NewStream(ctx, &xrayRControllerServiceDesc.Streams[1], methodWatchUserList, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &xrayRControllerWatchUserListClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type XrayRController_WatchUserListClient interface {
+	Recv() (*UserList, error)
+	grpc.ClientStream
+}
+
+type xrayRControllerWatchUserListClient struct {
+	grpc.ClientStream
+}
+
+func (x *xrayRControllerWatchUserListClient) Recv() (*UserList, error) {
+	m := new(UserList)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// XrayRControllerServer is the server API for the XrayRController service
+type XrayRControllerServer interface {
+	GetNodeInfo(context.Context, *NodeRequest) (*NodeInfo, error)
+	GetUserList(context.Context, *NodeRequest) (*UserList, error)
+	ReportNodeStatus(context.Context, *NodeStatus) (*Empty, error)
+	ReportNodeOnlineUsers(context.Context, *OnlineUserList) (*Empty, error)
+	ReportUserTraffic(context.Context, *UserTrafficList) (*Empty, error)
+	GetNodeRule(context.Context, *NodeRequest) (*DetectRuleList, error)
+	ReportIllegal(context.Context, *DetectResultList) (*Empty, error)
+	WatchNodeInfo(*NodeRequest, XrayRController_WatchNodeInfoServer) error
+	WatchUserList(*NodeRequest, XrayRController_WatchUserListServer) error
+}
+
+// UnimplementedXrayRControllerServer can be embedded in a Server
+// implementation to satisfy forward compatibility with new RPCs
+type UnimplementedXrayRControllerServer struct{}
+
+func (UnimplementedXrayRControllerServer) GetNodeInfo(context.Context, *NodeRequest) (*NodeInfo, error) {
+	return nil, grpcUnimplemented("GetNodeInfo")
+}
+func (UnimplementedXrayRControllerServer) GetUserList(context.Context, *NodeRequest) (*UserList, error) {
+	return nil, grpcUnimplemented("GetUserList")
+}
+func (UnimplementedXrayRControllerServer) ReportNodeStatus(context.Context, *NodeStatus) (*Empty, error) {
+	return nil, grpcUnimplemented("ReportNodeStatus")
+}
+func (UnimplementedXrayRControllerServer) ReportNodeOnlineUsers(context.Context, *OnlineUserList) (*Empty, error) {
+	return nil, grpcUnimplemented("ReportNodeOnlineUsers")
+}
+func (UnimplementedXrayRControllerServer) ReportUserTraffic(context.Context, *UserTrafficList) (*Empty, error) {
+	return nil, grpcUnimplemented("ReportUserTraffic")
+}
+func (UnimplementedXrayRControllerServer) GetNodeRule(context.Context, *NodeRequest) (*DetectRuleList, error) {
+	return nil, grpcUnimplemented("GetNodeRule")
+}
+func (UnimplementedXrayRControllerServer) ReportIllegal(context.Context, *DetectResultList) (*Empty, error) {
+	return nil, grpcUnimplemented("ReportIllegal")
+}
+func (UnimplementedXrayRControllerServer) WatchNodeInfo(*NodeRequest, XrayRController_WatchNodeInfoServer) error {
+	return grpcUnimplemented("WatchNodeInfo")
+}
+func (UnimplementedXrayRControllerServer) WatchUserList(*NodeRequest, XrayRController_WatchUserListServer) error {
+	return grpcUnimplemented("WatchUserList")
+}
+
+type XrayRController_WatchNodeInfoServer interface {
+	Send(*NodeInfo) error
+	grpc.ServerStream
+}
+
+type xrayRControllerWatchNodeInfoServer struct {
+	grpc.ServerStream
+}
+
+func (x *xrayRControllerWatchNodeInfoServer) Send(m *NodeInfo) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+type XrayRController_WatchUserListServer interface {
+	Send(*UserList) error
+	grpc.ServerStream
+}
+
+type xrayRControllerWatchUserListServer struct {
+	grpc.ServerStream
+}
+
+func (x *xrayRControllerWatchUserListServer) Send(m *UserList) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// RegisterXrayRControllerServer registers srv with s
+func RegisterXrayRControllerServer(s grpc.ServiceRegistrar, srv XrayRControllerServer) {
+	s.RegisterService(&xrayRControllerServiceDesc, srv)
+}
+
+func handleGetNodeInfo(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(XrayRControllerServer).GetNodeInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: methodGetNodeInfo}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(XrayRControllerServer).GetNodeInfo(ctx, req.(*NodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func handleGetUserList(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(XrayRControllerServer).GetUserList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: methodGetUserList}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(XrayRControllerServer).GetUserList(ctx, req.(*NodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func handleReportNodeStatus(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeStatus)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(XrayRControllerServer).ReportNodeStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: methodReportNodeStatus}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(XrayRControllerServer).ReportNodeStatus(ctx, req.(*NodeStatus))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func handleReportNodeOnlineUsers(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OnlineUserList)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(XrayRControllerServer).ReportNodeOnlineUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: methodReportNodeOnline}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(XrayRControllerServer).ReportNodeOnlineUsers(ctx, req.(*OnlineUserList))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func handleReportUserTraffic(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserTrafficList)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(XrayRControllerServer).ReportUserTraffic(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: methodReportUserTraffic}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(XrayRControllerServer).ReportUserTraffic(ctx, req.(*UserTrafficList))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func handleGetNodeRule(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(XrayRControllerServer).GetNodeRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: methodGetNodeRule}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(XrayRControllerServer).GetNodeRule(ctx, req.(*NodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func handleReportIllegal(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DetectResultList)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(XrayRControllerServer).ReportIllegal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: methodReportIllegal}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(XrayRControllerServer).ReportIllegal(ctx, req.(*DetectResultList))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func handleWatchNodeInfo(srv interface{}, stream grpc.ServerStream) error {
+	m := new(NodeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(XrayRControllerServer).WatchNodeInfo(m, &xrayRControllerWatchNodeInfoServer{stream})
+}
+
+func handleWatchUserList(srv interface{}, stream grpc.ServerStream) error {
+	m := new(NodeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(XrayRControllerServer).WatchUserList(m, &xrayRControllerWatchUserListServer{stream})
+}
+
+var xrayRControllerServiceDesc = grpc.ServiceDesc{
+	ServiceName: serviceName,
+	HandlerType: (*XrayRControllerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "GetNodeInfo", Handler: handleGetNodeInfo},
+		{MethodName: "GetUserList", Handler: handleGetUserList},
+		{MethodName: "ReportNodeStatus", Handler: handleReportNodeStatus},
+		{MethodName: "ReportNodeOnlineUsers", Handler: handleReportNodeOnlineUsers},
+		{MethodName: "ReportUserTraffic", Handler: handleReportUserTraffic},
+		{MethodName: "GetNodeRule", Handler: handleGetNodeRule},
+		{MethodName: "ReportIllegal", Handler: handleReportIllegal},
+	},
+	Streams: []grpc.StreamDesc{
+		{StreamName: "WatchNodeInfo", Handler: handleWatchNodeInfo, ServerStreams: true},
+		{StreamName: "WatchUserList", Handler: handleWatchUserList, ServerStreams: true},
+	},
+	Metadata: "proto/xrayr.proto",
+}
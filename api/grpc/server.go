@@ -0,0 +1,188 @@
+package grpc
+
+import (
+	"context"
+	"log"
+	"reflect"
+	"sort"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/realldz/XrayR/api"
+	"github.com/realldz/XrayR/api/grpc/pb"
+)
+
+// Server adapts any api.API (typically the real proxypanel client) into
+// the XrayRController gRPC service, so many edge XrayR nodes can
+// subscribe to it instead of every one of them polling the panel.
+type Server struct {
+	pb.UnimplementedXrayRControllerServer
+	backend   api.API
+	key       string
+	watchPoll time.Duration
+}
+
+// NewServer wraps backend, polling it every watchPoll to detect changes to
+// push to WatchNodeInfo/WatchUserList subscribers. key is the shared secret
+// every RPC must carry as "key" metadata, checked by ServerOptions'
+// interceptors before a call reaches backend.
+func NewServer(backend api.API, key string, watchPoll time.Duration) *Server {
+	if watchPoll <= 0 {
+		watchPoll = 10 * time.Second
+	}
+	return &Server{backend: backend, key: key, watchPoll: watchPoll}
+}
+
+// ServerOptions returns the grpc.ServerOptions that must be passed to
+// grpc.NewServer alongside this Server's transport credentials, so every
+// RPC is authenticated before reaching backend.
+func (s *Server) ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.UnaryInterceptor(unaryAuthInterceptor(s.key)),
+		grpc.StreamInterceptor(streamAuthInterceptor(s.key)),
+	}
+}
+
+// GetNodeInfo implements pb.XrayRControllerServer
+func (s *Server) GetNodeInfo(ctx context.Context, req *pb.NodeRequest) (*pb.NodeInfo, error) {
+	nodeInfo, err := s.backend.GetNodeInfo()
+	if err != nil {
+		return nil, err
+	}
+	return nodeInfoToPB(nodeInfo), nil
+}
+
+// GetUserList implements pb.XrayRControllerServer
+func (s *Server) GetUserList(ctx context.Context, req *pb.NodeRequest) (*pb.UserList, error) {
+	userList, err := s.backend.GetUserList()
+	if err != nil {
+		return nil, err
+	}
+	users := make([]*pb.UserInfo, len(*userList))
+	for i, u := range *userList {
+		users[i] = userInfoToPB(u)
+	}
+	return &pb.UserList{Users: users}, nil
+}
+
+// ReportNodeStatus implements pb.XrayRControllerServer
+func (s *Server) ReportNodeStatus(ctx context.Context, req *pb.NodeStatus) (*pb.Empty, error) {
+	err := s.backend.ReportNodeStatus(&api.NodeStatus{
+		CPU:    req.Cpu,
+		Mem:    req.Mem,
+		Disk:   req.Disk,
+		Uptime: req.Uptime,
+	})
+	return &pb.Empty{}, err
+}
+
+// ReportNodeOnlineUsers implements pb.XrayRControllerServer
+func (s *Server) ReportNodeOnlineUsers(ctx context.Context, req *pb.OnlineUserList) (*pb.Empty, error) {
+	users := make([]api.OnlineUser, len(req.Users))
+	for i, u := range req.Users {
+		users[i] = api.OnlineUser{UID: int(u.Uid), IP: u.Ip}
+	}
+	err := s.backend.ReportNodeOnlineUsers(&users)
+	return &pb.Empty{}, err
+}
+
+// ReportUserTraffic implements pb.XrayRControllerServer
+func (s *Server) ReportUserTraffic(ctx context.Context, req *pb.UserTrafficList) (*pb.Empty, error) {
+	traffic := make([]api.UserTraffic, len(req.Traffic))
+	for i, t := range req.Traffic {
+		traffic[i] = api.UserTraffic{UID: int(t.Uid), Upload: t.Upload, Download: t.Download}
+	}
+	err := s.backend.ReportUserTraffic(&traffic)
+	return &pb.Empty{}, err
+}
+
+// GetNodeRule implements pb.XrayRControllerServer
+func (s *Server) GetNodeRule(ctx context.Context, req *pb.NodeRequest) (*pb.DetectRuleList, error) {
+	ruleList, _, err := s.backend.GetNodeRule()
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]*pb.DetectRule, len(*ruleList))
+	for i, r := range *ruleList {
+		rules[i] = &pb.DetectRule{Id: int32(r.ID), Pattern: r.Pattern.String()}
+	}
+	return &pb.DetectRuleList{Rules: rules}, nil
+}
+
+// ReportIllegal implements pb.XrayRControllerServer
+func (s *Server) ReportIllegal(ctx context.Context, req *pb.DetectResultList) (*pb.Empty, error) {
+	results := make([]api.DetectResult, len(req.Results))
+	for i, r := range req.Results {
+		results[i] = api.DetectResult{
+			RuleID:    int(r.RuleId),
+			UID:       int(r.Uid),
+			Reason:    r.Reason,
+			Host:      r.Host,
+			Path:      r.Path,
+			Protocol:  r.Protocol,
+			Timestamp: r.Timestamp,
+		}
+	}
+	err := s.backend.ReportIllegal(&results)
+	return &pb.Empty{}, err
+}
+
+// WatchNodeInfo implements pb.XrayRControllerServer, pushing the node info
+// only when it changes instead of making the subscriber poll for it.
+func (s *Server) WatchNodeInfo(req *pb.NodeRequest, stream pb.XrayRController_WatchNodeInfoServer) error {
+	var last *pb.NodeInfo
+	ticker := time.NewTicker(s.watchPoll)
+	defer ticker.Stop()
+	for {
+		nodeInfo, err := s.backend.GetNodeInfo()
+		if err != nil {
+			log.Printf("WatchNodeInfo poll failed: %s", err)
+		} else {
+			current := nodeInfoToPB(nodeInfo)
+			if last == nil || !reflect.DeepEqual(last, current) {
+				if err := stream.Send(current); err != nil {
+					return err
+				}
+				last = current
+			}
+		}
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// WatchUserList implements pb.XrayRControllerServer, pushing the full user
+// list, sorted by UID, only when a user is added, removed or changed.
+func (s *Server) WatchUserList(req *pb.NodeRequest, stream pb.XrayRController_WatchUserListServer) error {
+	var last *pb.UserList
+	ticker := time.NewTicker(s.watchPoll)
+	defer ticker.Stop()
+	for {
+		userList, err := s.backend.GetUserList()
+		if err != nil {
+			log.Printf("WatchUserList poll failed: %s", err)
+		} else {
+			users := make([]*pb.UserInfo, len(*userList))
+			for i, u := range *userList {
+				users[i] = userInfoToPB(u)
+			}
+			sort.Slice(users, func(i, j int) bool { return users[i].Uid < users[j].Uid })
+			current := &pb.UserList{Users: users}
+			if last == nil || !reflect.DeepEqual(last, current) {
+				if err := stream.Send(current); err != nil {
+					return err
+				}
+				last = current
+			}
+		}
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case <-ticker.C:
+		}
+	}
+}
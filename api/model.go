@@ -0,0 +1,88 @@
+package api
+
+import (
+	"encoding/json"
+	"regexp"
+)
+
+// NodeInfo is the node information from the panel
+type NodeInfo struct {
+	NodeType          string
+	NodeID            int
+	Port              int
+	SpeedLimit        uint64
+	DeviceLimit       int
+	AlterID           int
+	TransportProtocol string
+	FakeType          string
+	CypherMethod      string
+	ServiceName       string
+	Header            json.RawMessage
+	EnableTLS         bool
+	TLSType           string
+	Path              string
+	Host              string
+	ServerName        string
+	EnableVless       bool
+	Flow              string
+	SSPassword        string
+	SSMethod          string
+}
+
+// UserInfo is the user information from the panel
+type UserInfo struct {
+	UID         int
+	Email       string
+	UUID        string
+	Passwd      string
+	SpeedLimit  uint64
+	DeviceLimit int
+	Flow        string
+}
+
+// NodeStatus is the node status for reporting
+type NodeStatus struct {
+	CPU    float64
+	Mem    float64
+	Disk   float64
+	Uptime uint64
+}
+
+// OnlineUser is an online user's UID/IP pair
+type OnlineUser struct {
+	UID int
+	IP  string
+}
+
+// UserTraffic is the traffic usage of a user
+type UserTraffic struct {
+	UID      int
+	Upload   int64
+	Download int64
+}
+
+// DetectRule is an audit rule used to flag illegal traffic
+type DetectRule struct {
+	ID      int
+	Pattern *regexp.Regexp
+}
+
+// DetectResult records the detail of an audit rule match. It is filled in
+// by the detector at match time, not by the panel client.
+type DetectResult struct {
+	RuleID    int
+	UID       int
+	Reason    string
+	Host      string
+	Path      string
+	Protocol  string
+	Timestamp int64
+}
+
+// ClientInfo describes the identity of the panel this client talks to
+type ClientInfo struct {
+	APIHost  string
+	NodeID   int
+	Key      string
+	NodeType string
+}
@@ -0,0 +1,59 @@
+package proxypanel
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// auditMu serializes writes to AuditLogPath across goroutines
+var auditMu sync.Mutex
+
+// auditRecord is one JSON-lines entry appended to AuditLogPath
+type auditRecord struct {
+	Time     string `json:"time"`
+	RuleID   int    `json:"rule_id"`
+	UID      int    `json:"uid"`
+	Reason   string `json:"reason"`
+	Host     string `json:"host,omitempty"`
+	Path     string `json:"path,omitempty"`
+	Protocol string `json:"protocol,omitempty"`
+}
+
+// auditLog appends one record to AuditLogPath if configured. Failures are
+// only logged, not returned.
+func (c *APIClient) auditLog(report IllegalReport) {
+	if c.AuditLogPath == "" {
+		return
+	}
+
+	line, err := json.Marshal(auditRecord{
+		Time:     time.Now().Format(time.RFC3339),
+		RuleID:   report.RuleID,
+		UID:      report.UID,
+		Reason:   report.Reason,
+		Host:     report.Host,
+		Path:     report.Path,
+		Protocol: report.Protocol,
+	})
+	if err != nil {
+		log.Printf("Marshal audit record failed: %s", err)
+		return
+	}
+
+	auditMu.Lock()
+	defer auditMu.Unlock()
+
+	file, err := os.OpenFile(c.AuditLogPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		log.Printf("Open audit log %s failed: %s", c.AuditLogPath, err)
+		return
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		log.Printf("Write audit log %s failed: %s", c.AuditLogPath, err)
+	}
+}
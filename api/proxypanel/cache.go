@@ -0,0 +1,155 @@
+package proxypanel
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/realldz/XrayR/api"
+	"github.com/realldz/XrayR/api/store/bolt"
+	"github.com/realldz/XrayR/api/store/kv"
+)
+
+// cache keys for the last successful GetNodeInfo/GetUserList responses, and
+// prefixes for the reports spooled while the panel is unreachable
+const (
+	cacheKeyNodeInfo = "node_info"
+	cacheKeyUserList = "user_list"
+	spoolKeyTraffic  = "spool/traffic/"
+	spoolKeyOnline   = "spool/online/"
+	spoolKeyIllegal  = "spool/illegal/"
+)
+
+// cacheEntry wraps a cached raw response with the time it was stored, so
+// cacheGet can honor CacheTTL
+type cacheEntry struct {
+	SavedAt int64           `json:"saved_at"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// newStore builds the Store backend selected by apiConfig.CacheBackend. A
+// nil return (CacheBackend empty, or a failed connection) leaves the client
+// running without a cache.
+func newStore(apiConfig *api.Config) api.Store {
+	switch apiConfig.CacheBackend {
+	case "":
+		return nil
+	case "bolt":
+		s, err := bolt.New(apiConfig.CachePath)
+		if err != nil {
+			log.Printf("Open bolt cache %s failed: %s", apiConfig.CachePath, err)
+			return nil
+		}
+		return s
+	case "etcd", "consul":
+		backend := kv.BackendEtcd
+		if apiConfig.CacheBackend == "consul" {
+			backend = kv.BackendConsul
+		}
+		addrs := strings.Split(apiConfig.CachePath, ",")
+		s, err := kv.New(backend, addrs, fmt.Sprintf("xrayr/%d/", apiConfig.NodeID))
+		if err != nil {
+			log.Printf("Connect to %s cache failed: %s", apiConfig.CacheBackend, err)
+			return nil
+		}
+		return s
+	default:
+		log.Printf("Unsupported cache backend: %s", apiConfig.CacheBackend)
+		return nil
+	}
+}
+
+// cachePut stores the last successful raw response under key
+func (c *APIClient) cachePut(key string, data *json.RawMessage) {
+	if c.cache == nil {
+		return
+	}
+	b, err := json.Marshal(cacheEntry{SavedAt: time.Now().Unix(), Data: *data})
+	if err != nil {
+		return
+	}
+	if err := c.cache.Put(key, b); err != nil {
+		log.Printf("Cache put %s failed: %s", key, err)
+	}
+}
+
+// cacheGet returns the last cached raw response for key if the cache is
+// enabled and the entry is still within CacheTTL (CacheTTL <= 0 means keep
+// serving the cached value indefinitely)
+func (c *APIClient) cacheGet(key string) (*json.RawMessage, bool) {
+	if c.cache == nil {
+		return nil, false
+	}
+	b, err := c.cache.Get(key)
+	if err != nil {
+		return nil, false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(b, &entry); err != nil {
+		return nil, false
+	}
+	if c.CacheTTL > 0 && time.Now().Unix()-entry.SavedAt > int64(c.CacheTTL) {
+		return nil, false
+	}
+	return &entry.Data, true
+}
+
+// spoolAppend persists a report batch that failed to reach the panel under
+// prefix, trimming the oldest entries once SpoolSize is exceeded
+func (c *APIClient) spoolAppend(prefix string, body []byte) {
+	if c.cache == nil {
+		return
+	}
+	key := fmt.Sprintf("%s%d", prefix, time.Now().UnixNano())
+	if err := c.cache.Put(key, body); err != nil {
+		log.Printf("Spool %s failed: %s", key, err)
+		return
+	}
+	c.trimSpool(prefix)
+}
+
+func (c *APIClient) trimSpool(prefix string) {
+	if c.SpoolSize <= 0 {
+		return
+	}
+	entries, err := c.cache.List(prefix)
+	if err != nil || len(entries) <= c.SpoolSize {
+		return
+	}
+	keys := spoolKeys(entries)
+	for _, k := range keys[:len(keys)-c.SpoolSize] {
+		c.cache.Delete(k)
+	}
+}
+
+// spoolReplay resends every pending batch under prefix oldest-first via
+// send, removing each entry once the panel accepts it. It stops at the
+// first failure so ordering is preserved and the rest are retried later.
+func (c *APIClient) spoolReplay(prefix string, send func([]byte) error) {
+	if c.cache == nil {
+		return
+	}
+	entries, err := c.cache.List(prefix)
+	if err != nil || len(entries) == 0 {
+		return
+	}
+	for _, k := range spoolKeys(entries) {
+		if err := send(entries[k]); err != nil {
+			log.Printf("Replay spooled report %s failed, will retry later: %s", k, err)
+			return
+		}
+		c.cache.Delete(k)
+	}
+}
+
+func spoolKeys(entries map[string][]byte) []string {
+	keys := make([]string, 0, len(entries))
+	for k := range entries {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,110 @@
+package proxypanel
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestCacheGetTTL(t *testing.T) {
+	c := &APIClient{cache: newMemStore()}
+	data := json.RawMessage(`{"a":1}`)
+
+	c.cachePut("k", &data)
+
+	c.CacheTTL = 0
+	if _, ok := c.cacheGet("k"); !ok {
+		t.Fatalf("cacheGet with CacheTTL <= 0 should always serve the cached value")
+	}
+
+	c.CacheTTL = 60
+	if _, ok := c.cacheGet("k"); !ok {
+		t.Fatalf("cacheGet within TTL should serve the cached value")
+	}
+
+	raw, _ := c.cache.Get("k")
+	var entry cacheEntry
+	json.Unmarshal(raw, &entry)
+	entry.SavedAt = time.Now().Unix() - 61
+	stale, _ := json.Marshal(entry)
+	c.cache.Put("k", stale)
+
+	if _, ok := c.cacheGet("k"); ok {
+		t.Fatalf("cacheGet past CacheTTL should not serve the cached value")
+	}
+}
+
+func TestSpoolKeys(t *testing.T) {
+	entries := map[string][]byte{
+		"spool/traffic/3": nil,
+		"spool/traffic/1": nil,
+		"spool/traffic/2": nil,
+	}
+	got := spoolKeys(entries)
+	want := []string{"spool/traffic/1", "spool/traffic/2", "spool/traffic/3"}
+	if len(got) != len(want) {
+		t.Fatalf("spoolKeys returned %d keys, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("spoolKeys()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestTrimSpool(t *testing.T) {
+	c := &APIClient{cache: newMemStore(), SpoolSize: 2}
+	for i := 0; i < 5; i++ {
+		c.spoolAppend(spoolKeyTraffic, []byte("x"))
+	}
+
+	entries, err := c.cache.List(spoolKeyTraffic)
+	if err != nil {
+		t.Fatalf("List failed: %s", err)
+	}
+	if len(entries) != c.SpoolSize {
+		t.Fatalf("trimSpool left %d entries, want %d", len(entries), c.SpoolSize)
+	}
+}
+
+// memStore is a minimal in-memory api.Store used only to exercise cache.go
+// without depending on the bolt or kv backends.
+type memStore struct {
+	data map[string][]byte
+}
+
+func newMemStore() *memStore {
+	return &memStore{data: make(map[string][]byte)}
+}
+
+func (s *memStore) Get(key string) ([]byte, error) {
+	v, ok := s.data[key]
+	if !ok {
+		return nil, errNotFoundStub{}
+	}
+	return v, nil
+}
+
+func (s *memStore) Put(key string, value []byte) error {
+	s.data[key] = value
+	return nil
+}
+
+func (s *memStore) List(prefix string) (map[string][]byte, error) {
+	result := make(map[string][]byte)
+	for k, v := range s.data {
+		if len(k) >= len(prefix) && k[:len(prefix)] == prefix {
+			result[k] = v
+		}
+	}
+	return result, nil
+}
+
+func (s *memStore) Delete(key string) error {
+	delete(s.data, key)
+	return nil
+}
+
+type errNotFoundStub struct{}
+
+func (errNotFoundStub) Error() string { return "not found" }
@@ -0,0 +1,114 @@
+package proxypanel
+
+import "encoding/json"
+
+// Response is the common envelope returned by every proxypanel endpoint
+type Response struct {
+	Status string          `json:"status"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// V2rayNodeInfo is the response data structure of GetNodeInfo for V2ray
+type V2rayNodeInfo struct {
+	V2Port      int    `json:"v2_port"`
+	V2AlterID   int    `json:"v2_alter_id"`
+	V2Net       string `json:"v2_net"`
+	V2Type      string `json:"v2_type"`
+	V2Host      string `json:"v2_host"`
+	V2Path      string `json:"v2_path"`
+	V2TLS       bool   `json:"v2_tls"`
+	SpeedLimit  int    `json:"speed_limit"`
+	ClientLimit int    `json:"client_limit"`
+}
+
+// ShadowsocksNodeInfo is the response data structure of GetNodeInfo for Shadowsocks
+type ShadowsocksNodeInfo struct {
+	Port        int    `json:"ss_port"`
+	Method      string `json:"method"`
+	SpeedLimit  int    `json:"speed_limit"`
+	ClientLimit int    `json:"client_limit"`
+}
+
+// TrojanNodeInfo is the response data structure of GetNodeInfo for Trojan
+type TrojanNodeInfo struct {
+	TrojanPort       int    `json:"trojan_port"`
+	SpeedLimit       int    `json:"speed_limit"`
+	ClientLimit      int    `json:"client_limit"`
+	TrojanNet        string `json:"trojan_net"`
+	TrojanHost       string `json:"trojan_host"`
+	TrojanPath       string `json:"trojan_path"`
+	TrojanSNI        string `json:"trojan_sni"`
+	TrojanSSPassword string `json:"trojan_ss_password"`
+	TrojanSSMethod   string `json:"trojan_ss_method"`
+}
+
+// VMessUser is the response data structure of GetUserList for V2ray
+type VMessUser struct {
+	UID         int    `json:"id"`
+	VmessUID    string `json:"vmess_uid"`
+	Flow        string `json:"flow"`
+	SpeedLimit  int    `json:"speed_limit"`
+	DeviceLimit int    `json:"device_limit"`
+	OnlineCount int    `json:"online_count"`
+}
+
+// TrojanUser is the response data structure of GetUserList for Trojan
+type TrojanUser struct {
+	UID         int    `json:"id"`
+	Password    string `json:"password"`
+	SpeedLimit  int    `json:"speed_limit"`
+	DeviceLimit int    `json:"device_limit"`
+	OnlineCount int    `json:"online_count"`
+}
+
+// SSUser is the response data structure of GetUserList for Shadowsocks
+type SSUser struct {
+	UID        int    `json:"id"`
+	Password   string `json:"password"`
+	SpeedLimit int    `json:"speed_limit"`
+}
+
+// NodeStatus is the request body of ReportNodeStatus
+type NodeStatus struct {
+	Uptime uint64 `json:"uptime"`
+	CPU    string `json:"cpu"`
+	Mem    string `json:"mem"`
+	Disk   string `json:"disk"`
+}
+
+// NodeOnline is an entry of the request body of ReportNodeOnlineUsers
+type NodeOnline struct {
+	UID int    `json:"uid"`
+	IP  string `json:"ip"`
+}
+
+// UserTraffic is an entry of the request body of ReportUserTraffic
+type UserTraffic struct {
+	UID      int   `json:"uid"`
+	Upload   int64 `json:"upload"`
+	Download int64 `json:"download"`
+}
+
+// IllegalReport is an entry of the request body of ReportIllegal
+type IllegalReport struct {
+	RuleID    int    `json:"rule_id"`
+	UID       int    `json:"uid"`
+	Reason    string `json:"reason"`
+	Host      string `json:"host,omitempty"`
+	Path      string `json:"path,omitempty"`
+	Protocol  string `json:"protocol,omitempty"`
+	Timestamp int64  `json:"timestamp,omitempty"`
+}
+
+// NodeRule is the response data structure of GetNodeRule
+type NodeRule struct {
+	Mode  string `json:"mode"`
+	Rules []Rule `json:"rules"`
+}
+
+// Rule is a single audit rule delivered by the panel
+type Rule struct {
+	ID      int    `json:"id"`
+	Type    string `json:"type"`
+	Pattern string `json:"pattern"`
+}
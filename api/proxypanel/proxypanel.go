@@ -16,6 +16,12 @@ import (
 	"github.com/realldz/XrayR/api"
 )
 
+// VLESS XTLS flow values, see the v2fly/xray VLESS inbound settings
+const (
+	VlessFlowDirect = "xtls-rprx-direct"
+	VlessFlowVision = "xtls-rprx-vision"
+)
+
 // APIClient create a api client to the panel.
 type APIClient struct {
 	client           *resty.Client
@@ -30,6 +36,16 @@ type APIClient struct {
 	LocalRuleList    []api.DetectRule
 	LastReportOnline map[int]int
 	access           sync.Mutex
+	cache            api.Store
+	CacheTTL         int
+	SpoolSize        int
+	UseWebSocket     bool
+	wsShadow         map[int]api.UserInfo
+	wsNodeInfo       *api.NodeInfo
+	wsMu             sync.Mutex
+	wsConnected      bool
+	wsDone           chan struct{}
+	AuditLogPath     string
 }
 
 // New creat a api instance
@@ -64,6 +80,15 @@ func New(apiConfig *api.Config) *APIClient {
 		DeviceLimit:      apiConfig.DeviceLimit,
 		LocalRuleList:    localRuleList,
 		LastReportOnline: make(map[int]int),
+		cache:            newStore(apiConfig),
+		CacheTTL:         apiConfig.CacheTTL,
+		SpoolSize:        apiConfig.SpoolSize,
+		UseWebSocket:     apiConfig.UseWebSocket,
+		AuditLogPath:     apiConfig.AuditLogPath,
+	}
+	if apiClient.UseWebSocket {
+		apiClient.wsDone = make(chan struct{})
+		apiClient.startWebSocket()
 	}
 	return apiClient
 }
@@ -125,6 +150,19 @@ func (c *APIClient) createCommonRequest() *resty.Request {
 	return request
 }
 
+// postJSON posts an already-marshaled body, used by the report endpoints so
+// a failed batch can be spooled and replayed verbatim later
+func (c *APIClient) postJSON(path string, body []byte) error {
+	res, err := c.createCommonRequest().
+		SetBody(body).
+		SetResult(&Response{}).
+		ForceContentType("application/json").
+		Post(path)
+
+	_, err = c.parseResponse(res, path, err)
+	return err
+}
+
 func (c *APIClient) parseResponse(res *resty.Response, path string, err error) (*Response, error) {
 	if err != nil {
 		return nil, fmt.Errorf("request %s failed: %s", c.assembleURL(path), err)
@@ -145,6 +183,12 @@ func (c *APIClient) parseResponse(res *resty.Response, path string, err error) (
 
 // GetNodeInfo will pull NodeInfo Config from sspanel
 func (c *APIClient) GetNodeInfo() (nodeInfo *api.NodeInfo, err error) {
+	if c.UseWebSocket {
+		if nodeInfo, ok := c.wsNode(); ok {
+			return nodeInfo, nil
+		}
+	}
+
 	var path string
 	switch c.NodeType {
 	case "V2ray":
@@ -164,7 +208,14 @@ func (c *APIClient) GetNodeInfo() (nodeInfo *api.NodeInfo, err error) {
 
 	response, err := c.parseResponse(res, path, err)
 	if err != nil {
-		return nil, err
+		cached, ok := c.cacheGet(cacheKeyNodeInfo)
+		if !ok {
+			return nil, err
+		}
+		log.Printf("GetNodeInfo failed (%s), serving cached node info", err)
+		response = &Response{Status: "success", Data: *cached}
+	} else {
+		c.cachePut(cacheKeyNodeInfo, &response.Data)
 	}
 
 	switch c.NodeType {
@@ -188,6 +239,12 @@ func (c *APIClient) GetNodeInfo() (nodeInfo *api.NodeInfo, err error) {
 
 // GetUserList will pull user form sspanel
 func (c *APIClient) GetUserList() (UserList *[]api.UserInfo, err error) {
+	if c.UseWebSocket {
+		if userList, ok := c.wsUserList(); ok {
+			return userList, nil
+		}
+	}
+
 	var path string
 	switch c.NodeType {
 	case "V2ray":
@@ -207,9 +264,17 @@ func (c *APIClient) GetUserList() (UserList *[]api.UserInfo, err error) {
 
 	response, err := c.parseResponse(res, path, err)
 	if err != nil {
-		return nil, err
+		cached, ok := c.cacheGet(cacheKeyUserList)
+		if !ok {
+			return nil, err
+		}
+		log.Printf("GetUserList failed (%s), serving cached user list", err)
+		response = &Response{Status: "success", Data: *cached}
+	} else {
+		c.cachePut(cacheKeyUserList, &response.Data)
 	}
 	userList := new([]api.UserInfo)
+	c.access.Lock()
 	switch c.NodeType {
 	case "V2ray":
 		userList, err = c.ParseV2rayUserListResponse(&response.Data)
@@ -218,8 +283,10 @@ func (c *APIClient) GetUserList() (UserList *[]api.UserInfo, err error) {
 	case "Shadowsocks":
 		userList, err = c.ParseSSUserListResponse(&response.Data)
 	default:
+		c.access.Unlock()
 		return nil, fmt.Errorf("Unsupported Node type: %s", c.NodeType)
 	}
+	c.access.Unlock()
 	if err != nil {
 		res, _ := json.Marshal(response.Data)
 		return nil, fmt.Errorf("Parse user list failed: %s", string(res))
@@ -288,17 +355,18 @@ func (c *APIClient) ReportNodeOnlineUsers(onlineUserList *[]api.OnlineUser) erro
 		}
 	}
 	c.LastReportOnline = reportOnline
-	res, err := c.createCommonRequest().
-		SetBody(data).
-		SetResult(&Response{}).
-		ForceContentType("application/json").
-		Post(path)
-
-	_, err = c.parseResponse(res, path, err)
+	body, err := json.Marshal(data)
 	if err != nil {
 		return err
 	}
 
+	c.spoolReplay(spoolKeyOnline, func(b []byte) error { return c.postJSON(path, b) })
+
+	if err := c.postJSON(path, body); err != nil {
+		c.spoolAppend(spoolKeyOnline, body)
+		return err
+	}
+
 	return nil
 }
 
@@ -323,17 +391,18 @@ func (c *APIClient) ReportUserTraffic(userTraffic *[]api.UserTraffic) error {
 			Upload:   traffic.Upload,
 			Download: traffic.Download}
 	}
-	res, err := c.createCommonRequest().
-		SetBody(data).
-		SetResult(&Response{}).
-		ForceContentType("application/json").
-		Post(path)
-
-	_, err = c.parseResponse(res, path, err)
+	body, err := json.Marshal(data)
 	if err != nil {
 		return err
 	}
 
+	c.spoolReplay(spoolKeyTraffic, func(b []byte) error { return c.postJSON(path, b) })
+
+	if err := c.postJSON(path, body); err != nil {
+		c.spoolAppend(spoolKeyTraffic, body)
+		return err
+	}
+
 	return nil
 }
 
@@ -399,26 +468,49 @@ func (c *APIClient) ReportIllegal(detectResultList *[]api.DetectResult) error {
 		return fmt.Errorf("Unsupported Node type: %s", c.NodeType)
 	}
 
-	for _, r := range *detectResultList {
-		res, err := c.createCommonRequest().
-			SetBody(IllegalReport{
-				RuleID: r.RuleID,
-				UID:    r.UID,
-				Reason: "XrayR cannot save reason",
-			}).
-			SetResult(&Response{}).
-			ForceContentType("application/json").
-			Post(path)
-
-		_, err = c.parseResponse(res, path, err)
-		if err != nil {
-			return err
+	reports := make([]IllegalReport, len(*detectResultList))
+	for i, r := range *detectResultList {
+		reports[i] = IllegalReport{
+			RuleID:    r.RuleID,
+			UID:       r.UID,
+			Reason:    r.Reason,
+			Host:      redactDetail(r.Host),
+			Path:      redactDetail(r.Path),
+			Protocol:  r.Protocol,
+			Timestamp: r.Timestamp,
 		}
+		c.auditLog(reports[i])
+	}
+
+	body, err := json.Marshal(reports)
+	if err != nil {
+		return err
+	}
+
+	c.spoolReplay(spoolKeyIllegal, func(b []byte) error { return c.postJSON(path, b) })
+
+	if err := c.postJSON(path, body); err != nil {
+		c.spoolAppend(spoolKeyIllegal, body)
+		return err
 	}
 
 	return nil
 }
 
+// redactDetail truncates an offending host/path snippet and masks its
+// middle portion, so the panel and the local audit log don't end up with a
+// full copy of user traffic contents.
+func redactDetail(s string) string {
+	const maxLen = 128
+	if len(s) > maxLen {
+		s = s[:maxLen]
+	}
+	if len(s) <= 8 {
+		return s
+	}
+	return s[:4] + "..." + s[len(s)-4:]
+}
+
 // ParseV2rayNodeResponse parse the response for the given nodeinfor format
 func (c *APIClient) ParseV2rayNodeResponse(nodeInfoResponse *json.RawMessage) (*api.NodeInfo, error) {
 	var TLStype string
@@ -452,6 +544,12 @@ func (c *APIClient) ParseV2rayNodeResponse(nodeInfoResponse *json.RawMessage) (*
 		in := `{"type":"http","request": {"path":"` + v2rayNodeInfo.V2Path + `"}}`
 		header = json.RawMessage(in)
 	}
+
+	var flow string
+	if c.EnableVless && c.EnableXTLS {
+		flow = VlessFlowDirect
+	}
+
 	// Create GeneralNodeInfo
 	nodeinfo := &api.NodeInfo{
 		NodeType:          c.NodeType,
@@ -469,6 +567,7 @@ func (c *APIClient) ParseV2rayNodeResponse(nodeInfoResponse *json.RawMessage) (*
 		Path:              v2rayNodeInfo.V2Path,
 		Host:              v2rayNodeInfo.V2Host,
 		EnableVless:       c.EnableVless,
+		Flow:              flow,
 	}
 
 	return nodeinfo, nil
@@ -535,6 +634,12 @@ func (c *APIClient) ParseTrojanNodeResponse(nodeInfoResponse *json.RawMessage) (
 	} else {
 		devicelimit = trojanNodeInfo.ClientLimit
 	}
+
+	transportProtocol := "tcp"
+	if trojanNodeInfo.TrojanNet != "" {
+		transportProtocol = trojanNodeInfo.TrojanNet
+	}
+
 	// Create GeneralNodeInfo
 	nodeinfo := &api.NodeInfo{
 		NodeType:          c.NodeType,
@@ -542,9 +647,14 @@ func (c *APIClient) ParseTrojanNodeResponse(nodeInfoResponse *json.RawMessage) (
 		Port:              trojanNodeInfo.TrojanPort,
 		DeviceLimit:       devicelimit,
 		SpeedLimit:        speedlimit,
-		TransportProtocol: "tcp",
+		TransportProtocol: transportProtocol,
 		EnableTLS:         true,
 		TLSType:           TLSType,
+		Host:              trojanNodeInfo.TrojanHost,
+		Path:              trojanNodeInfo.TrojanPath,
+		ServerName:        trojanNodeInfo.TrojanSNI,
+		SSPassword:        trojanNodeInfo.TrojanSSPassword,
+		SSMethod:          trojanNodeInfo.TrojanSSMethod,
 	}
 
 	return nodeinfo, nil
@@ -587,18 +697,49 @@ func (c *APIClient) ParseV2rayUserListResponse(userInfoResponse *json.RawMessage
 		} else if _, ok := c.LastReportOnline[user.UID]; user.OnlineCount == 0 && ok {
 			delete(c.LastReportOnline, user.UID)
 		}
+
+		var flow string
+		if c.EnableVless {
+			flow = c.validateVlessFlow(user.UID, user.Flow)
+		}
+
 		userList = append(userList, api.UserInfo{
 			UID:         user.UID,
 			Email:       "",
 			UUID:        user.VmessUID,
 			DeviceLimit: devicelimit,
 			SpeedLimit:  speedlimit,
+			Flow:        flow,
 		})
 	}
 
 	return &userList, nil
 }
 
+// validateVlessFlow checks that a user's requested flow is consistent with
+// EnableXTLS and falls back to the node default (or no flow) when it is not.
+func (c *APIClient) validateVlessFlow(uid int, flow string) string {
+	switch flow {
+	case "":
+		if c.EnableXTLS {
+			return VlessFlowDirect
+		}
+		return ""
+	case VlessFlowDirect, VlessFlowVision:
+		if !c.EnableXTLS {
+			log.Printf("User %d requested flow %s but XTLS is disabled on this node, ignoring", uid, flow)
+			return ""
+		}
+		return flow
+	default:
+		log.Printf("User %d requested unknown flow %s, ignoring", uid, flow)
+		if c.EnableXTLS {
+			return VlessFlowDirect
+		}
+		return ""
+	}
+}
+
 // ParseTrojanUserListResponse parse the response for the given userinfo format
 func (c *APIClient) ParseTrojanUserListResponse(userInfoResponse *json.RawMessage) (*[]api.UserInfo, error) {
 	var speedlimit uint64 = 0
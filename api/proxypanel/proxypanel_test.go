@@ -0,0 +1,54 @@
+package proxypanel
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateVlessFlow(t *testing.T) {
+	cases := []struct {
+		name       string
+		enableXTLS bool
+		flow       string
+		want       string
+	}{
+		{"no flow, xtls on defaults to direct", true, "", VlessFlowDirect},
+		{"no flow, xtls off stays empty", false, "", ""},
+		{"direct flow with xtls on", true, VlessFlowDirect, VlessFlowDirect},
+		{"vision flow with xtls on", true, VlessFlowVision, VlessFlowVision},
+		{"flow requested but xtls off is ignored", false, VlessFlowDirect, ""},
+		{"unknown flow with xtls on falls back to direct", true, "bogus-flow", VlessFlowDirect},
+		{"unknown flow with xtls off falls back to empty", false, "bogus-flow", ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client := &APIClient{EnableXTLS: c.enableXTLS}
+			if got := client.validateVlessFlow(1, c.flow); got != c.want {
+				t.Errorf("validateVlessFlow(%q) = %q, want %q", c.flow, got, c.want)
+			}
+		})
+	}
+}
+
+func TestRedactDetail(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"empty", "", ""},
+		{"short, unredacted", "abcd", "abcd"},
+		{"exactly 8 chars, unredacted", "abcdefgh", "abcdefgh"},
+		{"longer than 8, middle redacted", "abcdefghij", "abcd...ghij"},
+		{"over max length, truncated then redacted", strings.Repeat("a", 200), strings.Repeat("a", 4) + "..." + strings.Repeat("a", 4)},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := redactDetail(c.in); got != c.want {
+				t.Errorf("redactDetail(%d bytes) = %q, want %q", len(c.in), got, c.want)
+			}
+		})
+	}
+}
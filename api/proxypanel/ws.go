@@ -0,0 +1,277 @@
+package proxypanel
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/realldz/XrayR/api"
+)
+
+// wsReconnectDelay is how long to wait before redialing after the stream
+// drops, keeping a panel hiccup from turning into a reconnect storm.
+const wsReconnectDelay = 5 * time.Second
+
+// wsFrame is a single incremental update pushed by the panel's userList
+// stream, or a full resync snapshot right after the handshake/reconnect.
+type wsFrame struct {
+	Op   string          `json:"op"` // "add", "update", "del", "snapshot" or "node"
+	UID  int             `json:"uid,omitempty"`
+	User json.RawMessage `json:"user,omitempty"`
+	Node json.RawMessage `json:"node,omitempty"`
+}
+
+// wsHandshake authenticates the subscriber to the panel before it starts
+// pushing frames.
+type wsHandshake struct {
+	Nonce     string `json:"nonce"`
+	Timestamp int64  `json:"timestamp"`
+	Signature string `json:"signature"`
+}
+
+// startWebSocket runs the subscribe loop in the background, redialing and
+// resyncing (via a fresh snapshot frame) on every disconnect.
+func (c *APIClient) startWebSocket() {
+	go func() {
+		for {
+			select {
+			case <-c.wsDone:
+				return
+			default:
+			}
+			if err := c.runWebSocket(); err != nil {
+				log.Printf("Userlist WebSocket stream failed, reconnecting: %s", err)
+			}
+			c.wsSetConnected(false)
+			time.Sleep(wsReconnectDelay)
+		}
+	}()
+}
+
+func (c *APIClient) runWebSocket() error {
+	streamURL, err := c.wsURL()
+	if err != nil {
+		return err
+	}
+
+	conn, _, err := websocket.DefaultDialer.Dial(streamURL, nil)
+	if err != nil {
+		return fmt.Errorf("dial %s failed: %s", streamURL, err)
+	}
+	defer conn.Close()
+
+	if err := conn.WriteJSON(c.wsHandshakeFrame()); err != nil {
+		return fmt.Errorf("handshake failed: %s", err)
+	}
+
+	for {
+		var frame wsFrame
+		if err := conn.ReadJSON(&frame); err != nil {
+			return err
+		}
+		c.applyWSFrame(frame)
+		c.wsSetConnected(true)
+	}
+}
+
+// wsURL derives the stream endpoint from APIHost, upgrading http(s) to
+// ws(s).
+func (c *APIClient) wsURL() (string, error) {
+	streamPath, err := c.streamPath()
+	if err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(c.APIHost)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme == "https" {
+		u.Scheme = "wss"
+	} else {
+		u.Scheme = "ws"
+	}
+	u.Path = path.Join(u.Path, streamPath)
+	return u.String(), nil
+}
+
+func (c *APIClient) streamPath() (string, error) {
+	switch c.NodeType {
+	case "V2ray":
+		return fmt.Sprintf("/api/v2ray/v1/stream/%d", c.NodeID), nil
+	case "Trojan":
+		return fmt.Sprintf("/api/trojan/v1/stream/%d", c.NodeID), nil
+	case "Shadowsocks":
+		return fmt.Sprintf("/api/ss/v1/stream/%d", c.NodeID), nil
+	default:
+		return "", fmt.Errorf("Unsupported Node type: %s", c.NodeType)
+	}
+}
+
+// wsHandshakeFrame builds the nonce+timestamp+HMAC(Key) frame the panel
+// uses to authenticate the subscriber before it starts pushing updates.
+func (c *APIClient) wsHandshakeFrame() wsHandshake {
+	nonce := strconv.FormatInt(time.Now().UnixNano(), 36)
+	timestamp := time.Now().Unix()
+
+	mac := hmac.New(sha256.New, []byte(c.Key))
+	mac.Write([]byte(nonce))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+
+	return wsHandshake{
+		Nonce:     nonce,
+		Timestamp: timestamp,
+		Signature: hex.EncodeToString(mac.Sum(nil)),
+	}
+}
+
+// applyWSFrame updates the in-memory shadow map from a single pushed
+// frame, reusing the same per-node-type parsing (and speed/device limit
+// rules) as the HTTP GetUserList path.
+func (c *APIClient) applyWSFrame(frame wsFrame) {
+	switch frame.Op {
+	case "snapshot":
+		c.access.Lock()
+		userList, err := c.parseUserListRaw(frame.User)
+		c.access.Unlock()
+		if err != nil {
+			log.Printf("Parse WebSocket snapshot failed: %s", err)
+			return
+		}
+		shadow := make(map[int]api.UserInfo, len(*userList))
+		for _, u := range *userList {
+			shadow[u.UID] = u
+		}
+		c.wsMu.Lock()
+		c.wsShadow = shadow
+		c.wsMu.Unlock()
+	case "add", "update":
+		c.access.Lock()
+		user, err := c.parseSingleUser(frame.User)
+		c.access.Unlock()
+		if err != nil {
+			log.Printf("Parse WebSocket %s frame failed: %s", frame.Op, err)
+			return
+		}
+		c.wsMu.Lock()
+		if c.wsShadow == nil {
+			// no snapshot yet; the first snapshot carries this user
+		} else if user != nil {
+			c.wsShadow[user.UID] = *user
+		} else {
+			// filtered out by speed/device limit rules, same as a delete
+			delete(c.wsShadow, frame.UID)
+		}
+		c.wsMu.Unlock()
+	case "del":
+		c.wsMu.Lock()
+		delete(c.wsShadow, frame.UID)
+		c.wsMu.Unlock()
+	case "node":
+		nodeInfo, err := c.parseNodeInfoRaw(frame.Node)
+		if err != nil {
+			log.Printf("Parse WebSocket node frame failed: %s", err)
+			return
+		}
+		c.wsMu.Lock()
+		c.wsNodeInfo = nodeInfo
+		c.wsMu.Unlock()
+	default:
+		log.Printf("Unknown WebSocket frame op: %s", frame.Op)
+	}
+}
+
+// parseNodeInfoRaw parses a pushed node info payload with the existing
+// per-node-type parser.
+func (c *APIClient) parseNodeInfoRaw(raw json.RawMessage) (*api.NodeInfo, error) {
+	switch c.NodeType {
+	case "V2ray":
+		return c.ParseV2rayNodeResponse(&raw)
+	case "Trojan":
+		return c.ParseTrojanNodeResponse(&raw)
+	case "Shadowsocks":
+		return c.ParseSSNodeResponse(&raw)
+	default:
+		return nil, fmt.Errorf("Unsupported Node type: %s", c.NodeType)
+	}
+}
+
+// parseUserListRaw parses a full userList payload with the existing
+// per-node-type parser.
+func (c *APIClient) parseUserListRaw(raw json.RawMessage) (*[]api.UserInfo, error) {
+	switch c.NodeType {
+	case "V2ray":
+		return c.ParseV2rayUserListResponse(&raw)
+	case "Trojan":
+		return c.ParseTrojanUserListResponse(&raw)
+	case "Shadowsocks":
+		return c.ParseSSUserListResponse(&raw)
+	default:
+		return nil, fmt.Errorf("Unsupported Node type: %s", c.NodeType)
+	}
+}
+
+// parseSingleUser runs one pushed user entry through the per-node-type
+// parser by wrapping it in a one-element array. A nil result means the
+// user was filtered out (e.g. over the device limit).
+func (c *APIClient) parseSingleUser(raw json.RawMessage) (*api.UserInfo, error) {
+	wrapped, err := json.Marshal([]json.RawMessage{raw})
+	if err != nil {
+		return nil, err
+	}
+	rawList := json.RawMessage(wrapped)
+
+	userList, err := c.parseUserListRaw(rawList)
+	if err != nil {
+		return nil, err
+	}
+	if len(*userList) == 0 {
+		return nil, nil
+	}
+	return &(*userList)[0], nil
+}
+
+func (c *APIClient) wsSetConnected(connected bool) {
+	c.wsMu.Lock()
+	c.wsConnected = connected
+	c.wsMu.Unlock()
+}
+
+// wsUserList returns a snapshot of the in-memory shadow map built from
+// pushed WebSocket frames. ok is false until the stream is connected and
+// has delivered at least one snapshot, in which case the caller should
+// fall back to a plain HTTP GetUserList.
+func (c *APIClient) wsUserList() (*[]api.UserInfo, bool) {
+	c.wsMu.Lock()
+	defer c.wsMu.Unlock()
+	if !c.wsConnected || c.wsShadow == nil {
+		return nil, false
+	}
+	userList := make([]api.UserInfo, 0, len(c.wsShadow))
+	for _, u := range c.wsShadow {
+		userList = append(userList, u)
+	}
+	return &userList, true
+}
+
+// wsNode returns the last node info pushed over the WebSocket stream. ok is
+// false until the stream is connected and has delivered one, in which case
+// the caller should fall back to a plain HTTP GetNodeInfo.
+func (c *APIClient) wsNode() (*api.NodeInfo, bool) {
+	c.wsMu.Lock()
+	defer c.wsMu.Unlock()
+	if !c.wsConnected || c.wsNodeInfo == nil {
+		return nil, false
+	}
+	nodeInfo := *c.wsNodeInfo
+	return &nodeInfo, true
+}
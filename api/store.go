@@ -0,0 +1,17 @@
+package api
+
+import "errors"
+
+// ErrNotFound is returned by Store.Get when the key does not exist
+var ErrNotFound = errors.New("key not found")
+
+// Store is a pluggable key/value backend. The proxypanel client uses it to
+// persist the last known GetNodeInfo/GetUserList responses and to spool
+// reports that failed to reach the panel, so a panel outage doesn't cause
+// XrayR to wipe users or lose traffic samples.
+type Store interface {
+	Get(key string) ([]byte, error)
+	Put(key string, value []byte) error
+	List(prefix string) (map[string][]byte, error)
+	Delete(key string) error
+}
@@ -0,0 +1,98 @@
+// Package bolt implements api.Store on top of an embedded BoltDB file, for
+// single-node deployments that don't want an external KV dependency.
+package bolt
+
+import (
+	"go.etcd.io/bbolt"
+
+	"github.com/realldz/XrayR/api"
+)
+
+var bucketName = []byte("xrayr")
+
+// Store is a BoltDB-backed api.Store
+type Store struct {
+	db *bbolt.DB
+}
+
+// New opens (creating if necessary) a BoltDB file at path
+func New(path string) (*Store, error) {
+	db, err := bbolt.Open(path, 0600, nil)
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucketName)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+// Get implements api.Store
+func (s *Store) Get(key string) ([]byte, error) {
+	var value []byte
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		v := tx.Bucket(bucketName).Get([]byte(key))
+		if v == nil {
+			return api.ErrNotFound
+		}
+		value = append([]byte(nil), v...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// Put implements api.Store
+func (s *Store) Put(key string, value []byte) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(bucketName).Put([]byte(key), value)
+	})
+}
+
+// List implements api.Store
+func (s *Store) List(prefix string) (map[string][]byte, error) {
+	result := make(map[string][]byte)
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		c := tx.Bucket(bucketName).Cursor()
+		p := []byte(prefix)
+		for k, v := c.Seek(p); k != nil && hasPrefix(k, p); k, v = c.Next() {
+			result[string(k)] = append([]byte(nil), v...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Delete implements api.Store
+func (s *Store) Delete(key string) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(bucketName).Delete([]byte(key))
+	})
+}
+
+// Close releases the underlying BoltDB file
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+func hasPrefix(b, prefix []byte) bool {
+	if len(b) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if b[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
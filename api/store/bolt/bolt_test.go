@@ -0,0 +1,26 @@
+package bolt
+
+import "testing"
+
+func TestHasPrefix(t *testing.T) {
+	cases := []struct {
+		name   string
+		b      string
+		prefix string
+		want   bool
+	}{
+		{"exact match", "spool/", "spool/", true},
+		{"proper prefix", "spool/traffic/1", "spool/", true},
+		{"no match", "node_info", "spool/", false},
+		{"prefix longer than key", "sp", "spool/", false},
+		{"empty prefix always matches", "anything", "", true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := hasPrefix([]byte(c.b), []byte(c.prefix)); got != c.want {
+				t.Errorf("hasPrefix(%q, %q) = %v, want %v", c.b, c.prefix, got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+// Package kv implements api.Store on top of docker/libkv (etcd v2 and
+// consul backends), the same abstraction stolon uses for its store layer.
+package kv
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/libkv"
+	"github.com/docker/libkv/store"
+	"github.com/docker/libkv/store/consul"
+	"github.com/docker/libkv/store/etcd"
+
+	"github.com/realldz/XrayR/api"
+)
+
+// Backend selects the libkv-backed store implementation
+type Backend string
+
+const (
+	BackendEtcd   Backend = "etcd"
+	BackendConsul Backend = "consul"
+)
+
+// Store is a libkv-backed api.Store
+type Store struct {
+	kv     store.Store
+	prefix string
+}
+
+// New dials the given backend (etcd or consul) at addrs, namespacing all
+// keys under prefix
+func New(backend Backend, addrs []string, prefix string) (*Store, error) {
+	var libkvBackend store.Backend
+	switch backend {
+	case BackendEtcd:
+		etcd.Register()
+		libkvBackend = store.ETCD
+	case BackendConsul:
+		consul.Register()
+		libkvBackend = store.CONSUL
+	default:
+		return nil, fmt.Errorf("unsupported kv backend: %s", backend)
+	}
+
+	kv, err := libkv.NewStore(libkvBackend, addrs, nil)
+	if err != nil {
+		return nil, fmt.Errorf("connect to %s at %v failed: %s", backend, addrs, err)
+	}
+
+	return &Store{kv: kv, prefix: prefix}, nil
+}
+
+// Get implements api.Store
+func (s *Store) Get(key string) ([]byte, error) {
+	pair, err := s.kv.Get(s.prefix + key)
+	if err != nil {
+		if err == store.ErrKeyNotFound {
+			return nil, api.ErrNotFound
+		}
+		return nil, err
+	}
+	return pair.Value, nil
+}
+
+// Put implements api.Store
+func (s *Store) Put(key string, value []byte) error {
+	return s.kv.Put(s.prefix+key, value, nil)
+}
+
+// List implements api.Store
+func (s *Store) List(prefix string) (map[string][]byte, error) {
+	pairs, err := s.kv.List(s.prefix + prefix)
+	if err != nil {
+		if err == store.ErrKeyNotFound {
+			return map[string][]byte{}, nil
+		}
+		return nil, err
+	}
+	result := make(map[string][]byte, len(pairs))
+	for _, p := range pairs {
+		// strip etcd's leading slash and the namespace, so the returned
+		// keys can be passed back to Get/Delete like every other Store's
+		key := strings.TrimPrefix(p.Key, "/")
+		result[strings.TrimPrefix(key, s.prefix)] = p.Value
+	}
+	return result, nil
+}
+
+// Delete implements api.Store
+func (s *Store) Delete(key string) error {
+	return s.kv.Delete(s.prefix + key)
+}
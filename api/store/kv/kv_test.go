@@ -0,0 +1,129 @@
+package kv
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/docker/libkv/store"
+
+	"github.com/realldz/XrayR/api"
+)
+
+// fakeKV is a minimal in-memory store.Store, just enough of the interface
+// to exercise Store's Get/Put/List/Delete without a real etcd/consul.
+type fakeKV struct {
+	data map[string][]byte
+}
+
+func newFakeKV() *fakeKV {
+	return &fakeKV{data: make(map[string][]byte)}
+}
+
+func (f *fakeKV) Put(key string, value []byte, options *store.WriteOptions) error {
+	f.data[key] = value
+	return nil
+}
+
+func (f *fakeKV) Get(key string) (*store.KVPair, error) {
+	v, ok := f.data[key]
+	if !ok {
+		return nil, store.ErrKeyNotFound
+	}
+	return &store.KVPair{Key: key, Value: v}, nil
+}
+
+func (f *fakeKV) Delete(key string) error {
+	delete(f.data, key)
+	return nil
+}
+
+func (f *fakeKV) Exists(key string) (bool, error) {
+	_, ok := f.data[key]
+	return ok, nil
+}
+
+func (f *fakeKV) Watch(key string, stopCh <-chan struct{}) (<-chan *store.KVPair, error) {
+	return nil, store.ErrCallNotSupported
+}
+
+func (f *fakeKV) WatchTree(directory string, stopCh <-chan struct{}) (<-chan []*store.KVPair, error) {
+	return nil, store.ErrCallNotSupported
+}
+
+func (f *fakeKV) NewLock(key string, options *store.LockOptions) (store.Locker, error) {
+	return nil, store.ErrCallNotSupported
+}
+
+func (f *fakeKV) List(directory string) ([]*store.KVPair, error) {
+	var pairs []*store.KVPair
+	for k, v := range f.data {
+		if strings.HasPrefix(k, directory) {
+			pairs = append(pairs, &store.KVPair{Key: k, Value: v})
+		}
+	}
+	if len(pairs) == 0 {
+		return nil, store.ErrKeyNotFound
+	}
+	return pairs, nil
+}
+
+func (f *fakeKV) DeleteTree(directory string) error {
+	for k := range f.data {
+		if strings.HasPrefix(k, directory) {
+			delete(f.data, k)
+		}
+	}
+	return nil
+}
+
+func (f *fakeKV) AtomicPut(key string, value []byte, previous *store.KVPair, options *store.WriteOptions) (bool, *store.KVPair, error) {
+	return false, nil, store.ErrCallNotSupported
+}
+
+func (f *fakeKV) AtomicDelete(key string, previous *store.KVPair) (bool, error) {
+	return false, store.ErrCallNotSupported
+}
+
+func (f *fakeKV) Close() {}
+
+func TestStoreGetPutListDelete(t *testing.T) {
+	s := &Store{kv: newFakeKV(), prefix: "xrayr/1/"}
+
+	if err := s.Put("node_info", []byte("a")); err != nil {
+		t.Fatalf("Put failed: %s", err)
+	}
+	if err := s.Put("spool/traffic/1", []byte("b")); err != nil {
+		t.Fatalf("Put failed: %s", err)
+	}
+
+	got, err := s.Get("node_info")
+	if err != nil {
+		t.Fatalf("Get failed: %s", err)
+	}
+	if string(got) != "a" {
+		t.Errorf("Get() = %q, want %q", got, "a")
+	}
+
+	if _, err := s.Get("missing"); err != api.ErrNotFound {
+		t.Errorf("Get(missing) err = %v, want api.ErrNotFound", err)
+	}
+
+	entries, err := s.List("spool/")
+	if err != nil {
+		t.Fatalf("List failed: %s", err)
+	}
+	if len(entries) != 1 || string(entries["spool/traffic/1"]) != "b" {
+		t.Errorf("List(\"spool/\") = %v, want one entry {spool/traffic/1: b}", entries)
+	}
+
+	if entries, err := s.List("no/such/prefix/"); err != nil || len(entries) != 0 {
+		t.Errorf("List of an empty prefix = (%v, %v), want (empty map, nil)", entries, err)
+	}
+
+	if err := s.Delete("node_info"); err != nil {
+		t.Fatalf("Delete failed: %s", err)
+	}
+	if _, err := s.Get("node_info"); err != api.ErrNotFound {
+		t.Errorf("Get after Delete err = %v, want api.ErrNotFound", err)
+	}
+}